@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// mattermostTokens holds the shared secret(s) that an inbound slash-command POST must present,
+// set in main() from the MM_SLASH_TOKENS env var or, failing that, config. Multiple tokens are supported so a secret can be rotated
+// without downtime.
+var mattermostTokens []string
+
+// insecureGetMode, when true, allows the legacy unauthenticated GET form of this endpoint to
+// keep working (see -insecure-get) while operators migrate their Mattermost slash-command
+// configuration over to the signed POST contract.
+var insecureGetMode bool
+
+// validateMattermostToken reports whether token matches one of the configured mattermostTokens.
+func validateMattermostToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, configured := range mattermostTokens {
+		if token == configured {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateSlashCommand authenticates inboundRequest per the Mattermost slash-command
+// contract (a token-bearing application/x-www-form-urlencoded POST body) and returns the
+// command's "text" field on success. When insecureGetMode is enabled, a bare GET request is
+// also accepted, with its "text" taken from the query string instead, for backward
+// compatibility during migration. On failure, authenticateSlashCommand writes the appropriate
+// HTTP response itself and returns ok=false.
+func authenticateSlashCommand(ctx context.Context, responseWriter http.ResponseWriter, inboundRequest *http.Request) (text string, userID string, ok bool) {
+	log := requestLogger(ctx)
+
+	if insecureGetMode && inboundRequest.Method == http.MethodGet {
+		query := inboundRequest.URL.Query()
+		return query.Get("text"), query.Get("user_id"), true
+	}
+
+	if inboundRequest.Method != http.MethodPost {
+		http.Error(responseWriter, "method not allowed", http.StatusMethodNotAllowed)
+		return "", "", false
+	}
+
+	if err := inboundRequest.ParseForm(); err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusBadRequest)
+		return "", "", false
+	}
+
+	token := inboundRequest.PostForm.Get("token")
+	if !validateMattermostToken(token) {
+		log.Warn("rejected slash command: invalid or missing token", zap.String("user_name", inboundRequest.PostForm.Get("user_name")))
+		writeMattermostResponseWithStatus(responseWriter, http.StatusUnauthorized, buildMattermostResponse("ephemeral", "Invalid or missing Mattermost slash-command token."))
+		return "", "", false
+	}
+
+	log.Debug("authenticated slash command",
+		zap.String("user_name", inboundRequest.PostForm.Get("user_name")),
+		zap.String("channel_id", inboundRequest.PostForm.Get("channel_id")),
+	)
+
+	return inboundRequest.PostForm.Get("text"), inboundRequest.PostForm.Get("user_id"), true
+}
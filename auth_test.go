@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateMattermostToken(t *testing.T) {
+	mattermostTokens = []string{"token-a", "token-b"}
+	defer func() { mattermostTokens = nil }()
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"match", "token-a", true},
+		{"match rotated token", "token-b", true},
+		{"mismatch", "token-c", false},
+		{"missing token", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateMattermostToken(tt.token); got != tt.want {
+				t.Errorf("validateMattermostToken(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func postSlashCommand(token, text, userID string) *http.Request {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("text", text)
+	form.Set("user_id", userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestAuthenticateSlashCommandValidToken(t *testing.T) {
+	mattermostTokens = []string{"token-a"}
+	defer func() { mattermostTokens = nil }()
+
+	recorder := httptest.NewRecorder()
+	text, userID, ok := authenticateSlashCommand(context.Background(), recorder, postSlashCommand("token-a", "forecast London", "user-1"))
+
+	if !ok {
+		t.Fatalf("ok = false, want true (status %d)", recorder.Code)
+	}
+	if text != "forecast London" {
+		t.Errorf("text = %q, want %q", text, "forecast London")
+	}
+	if userID != "user-1" {
+		t.Errorf("userID = %q, want %q", userID, "user-1")
+	}
+}
+
+func TestAuthenticateSlashCommandInvalidToken(t *testing.T) {
+	mattermostTokens = []string{"token-a"}
+	defer func() { mattermostTokens = nil }()
+
+	recorder := httptest.NewRecorder()
+	_, _, ok := authenticateSlashCommand(context.Background(), recorder, postSlashCommand("wrong-token", "forecast London", "user-1"))
+
+	if ok {
+		t.Fatal("ok = true, want false for an invalid token")
+	}
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthenticateSlashCommandMissingToken(t *testing.T) {
+	mattermostTokens = []string{"token-a"}
+	defer func() { mattermostTokens = nil }()
+
+	recorder := httptest.NewRecorder()
+	_, _, ok := authenticateSlashCommand(context.Background(), recorder, postSlashCommand("", "forecast London", "user-1"))
+
+	if ok {
+		t.Fatal("ok = true, want false for a missing token")
+	}
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthenticateSlashCommandTokenRotation(t *testing.T) {
+	mattermostTokens = []string{"old-token", "new-token"}
+	defer func() { mattermostTokens = nil }()
+
+	for _, token := range []string{"old-token", "new-token"} {
+		recorder := httptest.NewRecorder()
+		_, _, ok := authenticateSlashCommand(context.Background(), recorder, postSlashCommand(token, "forecast London", "user-1"))
+		if !ok {
+			t.Errorf("token %q rejected during rotation, want accepted (status %d)", token, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultCacheTTL is used when no cacheTTL config/env/flag override is supplied.
+const defaultCacheTTL time.Duration = 10 * time.Minute
+
+// janitorInterval controls how often the background goroutine sweeps expired cache entries.
+const janitorInterval time.Duration = time.Minute
+
+// cacheEntry holds a single cached upstream JSON body alongside its expiry time.
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// ResponseCache memoizes upstream weather API responses, keyed by (provider, endpoint,
+// normalizedLocation), to avoid hitting the same upstream API repeatedly for popular
+// locations within the same TTL window.
+type ResponseCache struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+	ttl     time.Duration
+	hits    uint64
+	misses  uint64
+}
+
+// NewResponseCache creates a ResponseCache with the given TTL.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		entries: make(map[string]cacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// Get returns the cached body for key, if present and not expired. Expiry is checked lazily
+// here: a stale entry is reported as a miss but left in the map, and is only removed when the
+// janitor goroutine sweeps it or a later Set overwrites it.
+func (c *ResponseCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	entry, found := c.entries[key]
+	c.mu.RUnlock()
+
+	if !found || time.Now().After(entry.expiresAt) {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	return entry.body, true
+}
+
+// Set stores body under key, valid for the cache's configured TTL from now.
+func (c *ResponseCache) Set(key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{body: body, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// StartJanitor launches a background goroutine that periodically removes expired entries, so
+// that long-idle, no-longer-requested locations don't sit in memory forever.
+func (c *ResponseCache) StartJanitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.sweep()
+		}
+	}()
+}
+
+// sweep removes every expired entry from the cache.
+func (c *ResponseCache) sweep() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// cacheKeyFor builds the cache key for a given provider/endpoint/location triple, normalizing
+// the location so that "London" and "london" share an entry.
+func cacheKeyFor(provider providerName, endpoint string, location string) string {
+	return fmt.Sprintf("%s|%s|%s", provider, endpoint, strings.ToLower(strings.TrimSpace(location)))
+}
+
+// Stats reports hit/miss counters and the current number of cached entries.
+func (c *ResponseCache) Stats() (hits uint64, misses uint64, size int) {
+	c.mu.RLock()
+	size = len(c.entries)
+	c.mu.RUnlock()
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses), size
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logger is the process-wide structured logger, configured in main() from -log-level/-log-format
+// (and their LOG_LEVEL/LOG_FORMAT env equivalents). It defaults to a no-op logger so that code
+// paths exercised before main() finishes flag parsing don't panic on a nil logger.
+var logger *zap.Logger = zap.NewNop()
+
+// correlationIDKey is the context.Context key under which a request's correlation ID is stored.
+type correlationIDKey struct{}
+
+// newLogger builds a zap.Logger for the given level ("debug", "info", "warn", "error") and
+// format ("console" or "json").
+func newLogger(level string, format string) (*zap.Logger, error) {
+	var zapLevel zapcore.Level
+	if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, err
+	}
+
+	config := zap.NewProductionConfig()
+	if format == "console" {
+		config = zap.NewDevelopmentConfig()
+	}
+	config.Level = zap.NewAtomicLevelAt(zapLevel)
+
+	return config.Build()
+}
+
+// withCorrelationID returns a child context carrying correlationID, retrievable via
+// correlationIDFrom and automatically attached to every log line written via requestLogger.
+func withCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// correlationIDFrom extracts the correlation ID stashed by withCorrelationID, returning "" if
+// ctx doesn't carry one.
+func correlationIDFrom(ctx context.Context) string {
+	if value, ok := ctx.Value(correlationIDKey{}).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// requestLogger returns the package logger scoped with ctx's correlation ID, so every log line
+// emitted while handling a given slash command shares the same request_id field.
+func requestLogger(ctx context.Context) *zap.Logger {
+	return logger.With(zap.String("request_id", correlationIDFrom(ctx)))
+}
+
+// newCorrelationID generates a short random identifier used to tie together every log line
+// produced while handling a single inbound slash command.
+func newCorrelationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
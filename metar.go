@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// metarAPIBase is a var rather than a const so tests can point it at an httptest.Server
+// instead of the real avwx.rest.
+var metarAPIBase string = "https://avwx.rest/api/metar/%s?format=json"
+
+// metarAPIKey holds the bearer token used to authenticate against avwx.rest, set from
+// config/env/flag in main().
+var metarAPIKey string
+
+// icaoPattern validates that a station identifier is a bare four-letter ICAO code.
+var icaoPattern = regexp.MustCompile(`^[A-Za-z]{4}$`)
+
+// metarValue is avwx.rest's common shape for a decoded METAR field: a human-readable
+// representation alongside the numeric/string value we actually want to work with.
+type metarValue struct {
+	Repr  string  `json:"repr"`
+	Value float64 `json:"value"`
+}
+
+// MetarReport is the subset of an avwx.rest METAR response that we surface to the user.
+type MetarReport struct {
+	Raw           string     `json:"raw"`
+	FlightRules   string     `json:"flight_rules"`
+	Altimeter     metarValue `json:"altimeter"`
+	WindDirection metarValue `json:"wind_direction"`
+	WindSpeed     metarValue `json:"wind_speed"`
+	WindGust      metarValue `json:"wind_gust"`
+	Visibility    metarValue `json:"visibility"`
+	Dewpoint      metarValue `json:"dewpoint"`
+	Time          struct {
+		Dt string `json:"dt"`
+	} `json:"time"`
+}
+
+// handleMetarCommand validates the ICAO code, calls the METAR API and writes the decoded
+// response. An invalid station code is reported back as an ephemeral error.
+func handleMetarCommand(ctx context.Context, responseWriter http.ResponseWriter, args string) {
+	station := strings.ToUpper(strings.TrimSpace(args))
+
+	if !icaoPattern.MatchString(station) {
+		writeMattermostResponse(responseWriter, buildMattermostResponse("ephemeral", fmt.Sprintf("`%s` is not a valid four-letter ICAO station code.", station)))
+		return
+	}
+
+	report, err := callMetarAPI(ctx, station)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	message := formatMetarResponse(station, report)
+	writeMattermostResponse(responseWriter, buildMattermostResponse("in_channel", message))
+}
+
+// callMetarAPI calls avwx.rest for the given ICAO station and decodes the response into a MetarReport.
+func callMetarAPI(ctx context.Context, station string) (MetarReport, error) {
+	log := requestLogger(ctx)
+
+	fullURL := fmt.Sprintf(metarAPIBase, station)
+
+	request, err := http.NewRequest(http.MethodGet, fullURL, nil)
+	if err != nil {
+		return MetarReport{}, err
+	}
+	request.Header.Set("Authorization", "Bearer "+metarAPIKey)
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(request)
+	latencyMs := time.Since(start).Milliseconds()
+	if err != nil {
+		log.Error("call to METAR API failed", zap.String("location", station), zap.Error(err))
+		return MetarReport{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("unable to extract body data from METAR API response", zap.Error(err))
+		return MetarReport{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return MetarReport{}, fmt.Errorf("METAR API returned status %d for station %s", resp.StatusCode, station)
+	}
+
+	var report MetarReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		log.Error("failed to decode METAR API response", zap.Error(err))
+		return MetarReport{}, err
+	}
+
+	log.Info("called upstream METAR API",
+		zap.String("provider", "metar"),
+		zap.String("location", station),
+		zap.Int("upstream_status", resp.StatusCode),
+		zap.Int64("latency_ms", latencyMs),
+	)
+
+	return report, nil
+}
+
+// flightRulesEmoji returns the colour-coded emoji conventionally used for a flight category.
+func flightRulesEmoji(flightRules string) string {
+	switch strings.ToUpper(flightRules) {
+	case "VFR":
+		return "🟢"
+	case "MVFR":
+		return "🔵"
+	case "IFR":
+		return "🔴"
+	case "LIFR":
+		return "🟣"
+	default:
+		return "⚪"
+	}
+}
+
+// formatMetarResponse renders the raw METAR string in a fenced code block plus a decoded
+// summary (flight category, wind, visibility, dewpoint and station time).
+func formatMetarResponse(station string, report MetarReport) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "#### METAR for %s\n\n", station)
+	fmt.Fprintf(&builder, "```\n%s\n```\n\n", report.Raw)
+	fmt.Fprintf(&builder, "%s %s | Wind: %v° at %v kt gust %v kt | Visibility: %v sm | Dewpoint: %vÂ°C | Altimeter: %v | Station time: %s\n",
+		flightRulesEmoji(report.FlightRules), report.FlightRules, report.WindDirection.Value, report.WindSpeed.Value, report.WindGust.Value,
+		report.Visibility.Value, report.Dewpoint.Value, report.Altimeter.Value, report.Time.Dt)
+
+	return builder.String()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// metarFixture mirrors avwx.rest's actual shape, where numeric fields are nested
+// {"repr": ..., "value": ...} objects rather than bare scalars.
+const metarFixture = `{
+	"raw": "KJFK 271451Z 18010KT 10SM FEW250 24/18 A3005",
+	"flight_rules": "VFR",
+	"altimeter": {"repr": "3005", "value": 30.05},
+	"wind_direction": {"repr": "180", "value": 180},
+	"wind_speed": {"repr": "10", "value": 10},
+	"wind_gust": {"repr": "", "value": 0},
+	"visibility": {"repr": "10", "value": 10},
+	"dewpoint": {"repr": "18", "value": 18},
+	"time": {"dt": "2026-07-27T14:51:00Z"}
+}`
+
+func TestCallMetarAPI(t *testing.T) {
+	var gotPath string
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(metarFixture))
+	}))
+	defer server.Close()
+
+	origBase, origKey := metarAPIBase, metarAPIKey
+	metarAPIBase = server.URL + "/api/metar/%s?format=json"
+	metarAPIKey = "test-metar-key"
+	defer func() { metarAPIBase, metarAPIKey = origBase, origKey }()
+
+	report, err := callMetarAPI(context.Background(), "KJFK")
+	if err != nil {
+		t.Fatalf("callMetarAPI: %v", err)
+	}
+
+	if gotPath != "/api/metar/KJFK" {
+		t.Errorf("request path = %q, want %q", gotPath, "/api/metar/KJFK")
+	}
+	if gotAuth != "Bearer test-metar-key" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer test-metar-key")
+	}
+
+	if report.Altimeter.Value != 30.05 {
+		t.Errorf("Altimeter.Value = %v, want %v", report.Altimeter.Value, 30.05)
+	}
+	if report.WindSpeed.Value != 10 {
+		t.Errorf("WindSpeed.Value = %v, want %v", report.WindSpeed.Value, 10)
+	}
+	if report.Dewpoint.Value != 18 {
+		t.Errorf("Dewpoint.Value = %v, want %v", report.Dewpoint.Value, 18)
+	}
+	if report.Visibility.Value != 10 {
+		t.Errorf("Visibility.Value = %v, want %v", report.Visibility.Value, 10)
+	}
+
+	message := formatMetarResponse("KJFK", report)
+	if !strings.Contains(message, "Altimeter: 30.05") {
+		t.Errorf("expected formatted message to include the decoded altimeter value, got: %s", message)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// cacheStatsResponse is the JSON body served by metricsHandler.
+type cacheStatsResponse struct {
+	CacheHits   uint64 `json:"cache_hits"`
+	CacheMisses uint64 `json:"cache_misses"`
+	CacheSize   int    `json:"cache_size"`
+}
+
+// metricsHandler exposes response-cache hit/miss/size counters so operators can see whether
+// the cache is actually absorbing repeat requests for popular locations.
+func metricsHandler(responseWriter http.ResponseWriter, inboundRequest *http.Request) {
+	hits, misses, size := responseCache.Stats()
+
+	jsonResponse, err := json.Marshal(cacheStatsResponse{
+		CacheHits:   hits,
+		CacheMisses: misses,
+		CacheSize:   size,
+	})
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	_, writeErr := responseWriter.Write(jsonResponse)
+	if writeErr != nil {
+		logger.Error("error writing metrics response", zap.Error(writeErr))
+	}
+}
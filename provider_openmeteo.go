@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/buger/jsonparser"
+)
+
+const (
+	openMeteoGeocodingURL string = "https://geocoding-api.open-meteo.com/v1/search"
+	openMeteoForecastURL  string = "https://api.open-meteo.com/v1/forecast"
+)
+
+// OpenMeteoProvider talks to Open-Meteo, which requires no API key/registration. Unlike the
+// other providers it addresses locations by latitude/longitude, so every call first resolves
+// the requested location via Open-Meteo's own geocoding endpoint.
+type OpenMeteoProvider struct{}
+
+// geocodeLocation resolves a free-text location name to a latitude/longitude pair and the
+// canonical location name Open-Meteo knows it by.
+func geocodeLocation(ctx context.Context, location string) (name string, latitude float64, longitude float64, err error) {
+	safeLocation := url.QueryEscape(location)
+	fullURL := fmt.Sprintf("%s?name=%s&count=1", openMeteoGeocodingURL, safeLocation)
+
+	body, err := fetchJSON(ctx, fullURL, providerOpenMeteo, "geocode", location)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	results, _, _, resultsErr := jsonparser.Get(body, "results")
+	if resultsErr != nil {
+		return "", 0, 0, fmt.Errorf("unable to find a location matching %q", location)
+	}
+
+	var firstResult []byte
+	_, _ = jsonparser.ArrayEach(results, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if firstResult == nil {
+			firstResult = value
+		}
+	})
+	if firstResult == nil {
+		return "", 0, 0, fmt.Errorf("unable to find a location matching %q", location)
+	}
+
+	name, _ = jsonparser.GetString(firstResult, "name")
+	latitude, _ = jsonparser.GetFloat(firstResult, "latitude")
+	longitude, _ = jsonparser.GetFloat(firstResult, "longitude")
+	return name, latitude, longitude, nil
+}
+
+// weatherCodeToText converts an Open-Meteo/WMO weather code into a short human-readable summary.
+func weatherCodeToText(code int64) string {
+	switch {
+	case code == 0:
+		return "Clear sky"
+	case code <= 3:
+		return "Partly cloudy"
+	case code <= 48:
+		return "Fog"
+	case code <= 67:
+		return "Rain"
+	case code <= 77:
+		return "Snow"
+	case code <= 82:
+		return "Rain showers"
+	case code <= 86:
+		return "Snow showers"
+	case code <= 99:
+		return "Thunderstorm"
+	default:
+		return "Unknown"
+	}
+}
+
+// Current implements WeatherProvider.
+func (p *OpenMeteoProvider) Current(ctx context.Context, location string) (Observation, error) {
+	name, latitude, longitude, err := geocodeLocation(ctx, location)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	fullURL := fmt.Sprintf("%s?latitude=%v&longitude=%v&current_weather=true", openMeteoForecastURL, latitude, longitude)
+	body, err := fetchJSON(ctx, fullURL, providerOpenMeteo, "current", location)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	tempC, _ := jsonparser.GetFloat(body, "current_weather", "temperature")
+	weatherCode, _ := jsonparser.GetInt(body, "current_weather", "weathercode")
+
+	return Observation{
+		LocationName: name,
+		TempC:        tempC,
+		TempF:        tempC*1.8 + 32,
+		Conditions:   weatherCodeToText(weatherCode),
+	}, nil
+}
+
+// Forecast implements WeatherProvider.
+func (p *OpenMeteoProvider) Forecast(ctx context.Context, location string, days int) ([]DayForecast, error) {
+	name, latitude, longitude, err := geocodeLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	_ = name
+
+	fullURL := fmt.Sprintf(
+		"%s?latitude=%v&longitude=%v&daily=temperature_2m_max,temperature_2m_min,precipitation_probability_max,weathercode&timezone=auto&forecast_days=%d",
+		openMeteoForecastURL, latitude, longitude, days)
+	body, err := fetchJSON(ctx, fullURL, providerOpenMeteo, fmt.Sprintf("forecast:%d", days), location)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecastDays []DayForecast
+	_, _ = jsonparser.ArrayEach(body, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		forecastDays = append(forecastDays, DayForecast{Date: string(value)})
+	}, "daily", "time")
+
+	maxTemps := readFloatArray(body, "daily", "temperature_2m_max")
+	minTemps := readFloatArray(body, "daily", "temperature_2m_min")
+	chanceOfRain := readFloatArray(body, "daily", "precipitation_probability_max")
+	weatherCodes := readIntArray(body, "daily", "weathercode")
+
+	for i := range forecastDays {
+		if i < len(maxTemps) {
+			forecastDays[i].MaxC = maxTemps[i]
+		}
+		if i < len(minTemps) {
+			forecastDays[i].MinC = minTemps[i]
+		}
+		if i < len(chanceOfRain) {
+			forecastDays[i].ChanceOfRain = chanceOfRain[i]
+		}
+		if i < len(weatherCodes) {
+			forecastDays[i].Conditions = weatherCodeToText(weatherCodes[i])
+		}
+	}
+
+	return forecastDays, nil
+}
+
+// readFloatArray reads a flat JSON array of numbers at the given key path.
+func readFloatArray(data []byte, keys ...string) []float64 {
+	var values []float64
+	_, _ = jsonparser.ArrayEach(data, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		parsed, parseErr := jsonparser.ParseFloat(value)
+		if parseErr == nil {
+			values = append(values, parsed)
+		}
+	}, keys...)
+	return values
+}
+
+// readIntArray reads a flat JSON array of integers at the given key path.
+func readIntArray(data []byte, keys ...string) []int64 {
+	var values []int64
+	_, _ = jsonparser.ArrayEach(data, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		parsed, parseErr := jsonparser.ParseInt(value)
+		if parseErr == nil {
+			values = append(values, parsed)
+		}
+	}, keys...)
+	return values
+}
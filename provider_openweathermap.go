@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+// owmCurrentURL and owmForecastURL are vars rather than consts so tests can point them at an
+// httptest.Server instead of the real OpenWeatherMap.
+var (
+	owmCurrentURL  string = "https://api.openweathermap.org/data/2.5/weather"
+	owmForecastURL string = "https://api.openweathermap.org/data/2.5/forecast"
+)
+
+// kelvinToCelsius converts an OpenWeatherMap temperature (always reported in Kelvin) to Celsius.
+func kelvinToCelsius(kelvin float64) float64 {
+	return kelvin - 273.15
+}
+
+// kelvinToFahrenheit converts an OpenWeatherMap temperature (always reported in Kelvin) to Fahrenheit.
+func kelvinToFahrenheit(kelvin float64) float64 {
+	return (kelvin-273.15)*1.8 + 32
+}
+
+// mpsToKph converts an OpenWeatherMap wind speed (always reported in m/s) to km/h.
+func mpsToKph(windMps float64) float64 {
+	return windMps * 3.6
+}
+
+// OpenWeatherMapProvider talks to OpenWeatherMap's "coord/weather/main/wind/sys" schema.
+type OpenWeatherMapProvider struct {
+	apiKey string
+}
+
+// Current implements WeatherProvider.
+func (p *OpenWeatherMapProvider) Current(ctx context.Context, location string) (Observation, error) {
+	safeLocation := url.QueryEscape(location)
+	fullURL := fmt.Sprintf("%s?q=%s&appid=%s", owmCurrentURL, safeLocation, p.apiKey)
+
+	body, err := fetchJSON(ctx, fullURL, providerOpenWeatherMap, "current", location)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	locationName, _ := jsonparser.GetString(body, "name")
+	tempKelvin, _ := jsonparser.GetFloat(body, "main", "temp")
+	conditions, _ := jsonparser.GetString(body, "weather", "[0]", "description")
+
+	return Observation{
+		LocationName: locationName,
+		TempC:        kelvinToCelsius(tempKelvin),
+		TempF:        kelvinToFahrenheit(tempKelvin),
+		Conditions:   conditions,
+	}, nil
+}
+
+// Forecast implements WeatherProvider. OpenWeatherMap's free tier only exposes a 3-hourly,
+// 5-day forecast, so we group the individual samples by calendar date and derive daily
+// min/max/conditions/chance-of-rain from them; each sample is also kept as an hourly entry so
+// the "hourly" subcommand has real rows rather than just a table header.
+func (p *OpenWeatherMapProvider) Forecast(ctx context.Context, location string, days int) ([]DayForecast, error) {
+	safeLocation := url.QueryEscape(location)
+	fullURL := fmt.Sprintf("%s?q=%s&appid=%s", owmForecastURL, safeLocation, p.apiKey)
+
+	body, err := fetchJSON(ctx, fullURL, providerOpenWeatherMap, "forecast", location)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := map[string]*DayForecast{}
+	var order []string
+
+	_, _ = jsonparser.ArrayEach(body, func(sampleValue []byte, dataType jsonparser.ValueType, offset int, err error) {
+		dtTxt, _ := jsonparser.GetString(sampleValue, "dt_txt")
+		date := strings.SplitN(dtTxt, " ", 2)[0]
+		tempKelvin, _ := jsonparser.GetFloat(sampleValue, "main", "temp")
+		conditions, _ := jsonparser.GetString(sampleValue, "weather", "[0]", "description")
+		// pop is OpenWeatherMap's probability-of-precipitation for this 3-hour sample, expressed
+		// as a 0-1 fraction rather than weatherapi.com's 0-100 daily_chance_of_rain.
+		pop, _ := jsonparser.GetFloat(sampleValue, "pop")
+		chanceOfRain := pop * 100
+		windMps, _ := jsonparser.GetFloat(sampleValue, "wind", "speed")
+		windKph := mpsToKph(windMps)
+		tempC := kelvinToCelsius(tempKelvin)
+
+		day, exists := byDate[date]
+		if !exists {
+			day = &DayForecast{Date: date, MinC: tempC, MaxC: tempC, Conditions: conditions}
+			byDate[date] = day
+			order = append(order, date)
+		}
+		if tempC < day.MinC {
+			day.MinC = tempC
+		}
+		if tempC > day.MaxC {
+			day.MaxC = tempC
+		}
+		if chanceOfRain > day.ChanceOfRain {
+			day.ChanceOfRain = chanceOfRain
+		}
+		day.Hours = append(day.Hours, HourForecast{
+			Time:         dtTxt,
+			TempC:        tempC,
+			ChanceOfRain: chanceOfRain,
+			Conditions:   conditions,
+			WindKph:      windKph,
+		})
+	}, "list")
+
+	sort.Strings(order)
+
+	var forecastDays []DayForecast
+	for i, date := range order {
+		if i >= days {
+			break
+		}
+		forecastDays = append(forecastDays, *byDate[date])
+	}
+
+	return forecastDays, nil
+}
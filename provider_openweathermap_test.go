@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const owmForecastFixture = `{
+	"list": [
+		{"dt_txt": "2026-07-27 00:00:00", "main": {"temp": 286.15}, "weather": [{"description": "clear sky"}], "pop": 0.1, "wind": {"speed": 2.0}},
+		{"dt_txt": "2026-07-27 03:00:00", "main": {"temp": 284.15}, "weather": [{"description": "light rain"}], "pop": 0.6, "wind": {"speed": 4.5}}
+	]
+}`
+
+func TestOpenWeatherMapProviderForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(owmForecastFixture))
+	}))
+	defer server.Close()
+
+	origForecast := owmForecastURL
+	owmForecastURL = server.URL
+	defer func() { owmForecastURL = origForecast }()
+	responseCache = NewResponseCache(defaultCacheTTL)
+
+	provider := &OpenWeatherMapProvider{apiKey: "test-key"}
+	days, err := provider.Forecast(context.Background(), "London", 1)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+
+	if len(days) != 1 {
+		t.Fatalf("got %d days, want 1", len(days))
+	}
+
+	day := days[0]
+	if day.ChanceOfRain != 60 {
+		t.Errorf("ChanceOfRain = %v, want %v (the day's highest sample pop, as a percentage)", day.ChanceOfRain, 60.0)
+	}
+	if len(day.Hours) != 2 {
+		t.Fatalf("got %d hourly entries, want 2", len(day.Hours))
+	}
+	if day.Hours[1].ChanceOfRain != 60 || day.Hours[1].Conditions != "light rain" {
+		t.Errorf("unexpected second hourly entry: %+v", day.Hours[1])
+	}
+	if day.Hours[0].WindKph <= 0 {
+		t.Errorf("WindKph = %v, want a converted, non-zero wind speed", day.Hours[0].WindKph)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"go.uber.org/zap"
+)
+
+// weatherAPICurrentURL and weatherAPIForecastURL are vars rather than consts so tests can point
+// them at an httptest.Server instead of the real weatherapi.com.
+var (
+	weatherAPICurrentURL  string = "http://api.weatherapi.com/v1/current.json"
+	weatherAPIForecastURL string = "http://api.weatherapi.com/v1/forecast.json"
+)
+
+const weatherAPISuffix string = "aqi=no"
+
+// WeatherAPIProvider talks to weatherapi.com, the original (and default) backend for this tool.
+type WeatherAPIProvider struct {
+	apiKey string
+}
+
+// Current implements WeatherProvider.
+func (p *WeatherAPIProvider) Current(ctx context.Context, location string) (Observation, error) {
+	safeLocation := url.QueryEscape(location)
+	fullURL := fmt.Sprintf("%s?key=%s&q=%s&%s", weatherAPICurrentURL, p.apiKey, safeLocation, weatherAPISuffix)
+
+	body, err := fetchJSON(ctx, fullURL, providerWeatherAPI, "current", location)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	locationName, _ := jsonparser.GetString(body, "location", "name")
+	tempC, _ := jsonparser.GetFloat(body, "current", "temp_c")
+	tempF, _ := jsonparser.GetFloat(body, "current", "temp_f")
+	conditions, _ := jsonparser.GetString(body, "current", "condition", "text")
+
+	return Observation{
+		LocationName: locationName,
+		TempC:        tempC,
+		TempF:        tempF,
+		Conditions:   conditions,
+	}, nil
+}
+
+// Forecast implements WeatherProvider.
+func (p *WeatherAPIProvider) Forecast(ctx context.Context, location string, days int) ([]DayForecast, error) {
+	safeLocation := url.QueryEscape(location)
+	fullURL := fmt.Sprintf("%s?key=%s&q=%s&days=%d&%s", weatherAPIForecastURL, p.apiKey, safeLocation, days, weatherAPISuffix)
+
+	body, err := fetchJSON(ctx, fullURL, providerWeatherAPI, fmt.Sprintf("forecast:%d", days), location)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecastDays []DayForecast
+	_, _ = jsonparser.ArrayEach(body, func(dayValue []byte, dataType jsonparser.ValueType, offset int, err error) {
+		date, _ := jsonparser.GetString(dayValue, "date")
+		minTemp, _ := jsonparser.GetFloat(dayValue, "day", "mintemp_c")
+		maxTemp, _ := jsonparser.GetFloat(dayValue, "day", "maxtemp_c")
+		chanceOfRain, _ := jsonparser.GetFloat(dayValue, "day", "daily_chance_of_rain")
+		conditions, _ := jsonparser.GetString(dayValue, "day", "condition", "text")
+		sunrise, _ := jsonparser.GetString(dayValue, "astro", "sunrise")
+		sunset, _ := jsonparser.GetString(dayValue, "astro", "sunset")
+		moonrise, _ := jsonparser.GetString(dayValue, "astro", "moonrise")
+
+		var hours []HourForecast
+		_, _ = jsonparser.ArrayEach(dayValue, func(hourValue []byte, dataType jsonparser.ValueType, offset int, err error) {
+			hourTime, _ := jsonparser.GetString(hourValue, "time")
+			hourTempC, _ := jsonparser.GetFloat(hourValue, "temp_c")
+			hourChanceOfRain, _ := jsonparser.GetFloat(hourValue, "chance_of_rain")
+			hourConditions, _ := jsonparser.GetString(hourValue, "condition", "text")
+			windKph, _ := jsonparser.GetFloat(hourValue, "wind_kph")
+
+			hours = append(hours, HourForecast{
+				Time:         hourTime,
+				TempC:        hourTempC,
+				ChanceOfRain: hourChanceOfRain,
+				Conditions:   hourConditions,
+				WindKph:      windKph,
+			})
+		}, "hour")
+
+		forecastDays = append(forecastDays, DayForecast{
+			Date:         date,
+			MinC:         minTemp,
+			MaxC:         maxTemp,
+			ChanceOfRain: chanceOfRain,
+			Conditions:   conditions,
+			Sunrise:      sunrise,
+			Sunset:       sunset,
+			Moonrise:     moonrise,
+			Hours:        hours,
+		})
+	}, "forecast", "forecastday")
+
+	return forecastDays, nil
+}
+
+// fetchJSON performs a GET request against fullURL and returns the decoded/re-marshalled body,
+// serving a cached copy when one exists under the (provider, endpoint, location) cache key and
+// hasn't expired. Shared by every WeatherProvider implementation that speaks plain JSON over
+// HTTP. Every log line it emits carries the request's correlation ID via ctx.
+func fetchJSON(ctx context.Context, fullURL string, provider providerName, endpoint string, location string) ([]byte, error) {
+	log := requestLogger(ctx)
+	cacheKey := cacheKeyFor(provider, endpoint, location)
+
+	if cached, found := responseCache.Get(cacheKey); found {
+		log.Debug("cache hit", zap.String("provider", string(provider)), zap.String("location", location))
+		return cached, nil
+	}
+
+	start := time.Now()
+	resp, err := http.Get(fullURL)
+	latencyMs := time.Since(start).Milliseconds()
+	if err != nil {
+		log.Error("call to weather API failed", zap.String("provider", string(provider)), zap.String("location", location), zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("unable to extract body data from weather API response", zap.String("provider", string(provider)), zap.Error(err))
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("weather API returned a non-2xx status", zap.String("provider", string(provider)), zap.String("location", location), zap.Int("upstream_status", resp.StatusCode))
+		return nil, fmt.Errorf("%s API returned status %d for %s", provider, resp.StatusCode, location)
+	}
+
+	// Round-trip through a generic map so that callers get back a validated, canonical JSON
+	// document regardless of what whitespace/ordering the upstream API used.
+	var result map[string]interface{}
+	if err := json.Unmarshal(rawBody, &result); err != nil {
+		log.Error("failed to convert body data", zap.String("provider", string(provider)), zap.Error(err))
+		return nil, err
+	}
+
+	weatherData, err := json.Marshal(result)
+	if err != nil {
+		log.Error("unable to convert weather data to string", zap.String("provider", string(provider)), zap.Error(err))
+		return nil, err
+	}
+
+	log.Info("called upstream weather API",
+		zap.String("provider", string(provider)),
+		zap.String("location", location),
+		zap.Int("upstream_status", resp.StatusCode),
+		zap.Int64("latency_ms", latencyMs),
+	)
+
+	responseCache.Set(cacheKey, weatherData)
+
+	return weatherData, nil
+}
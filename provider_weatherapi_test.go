@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const currentFixture = `{
+	"location": {"name": "London"},
+	"current": {"temp_c": 18.5, "temp_f": 65.3, "condition": {"text": "Partly cloudy"}}
+}`
+
+const forecastFixture = `{
+	"forecast": {
+		"forecastday": [
+			{
+				"date": "2026-07-27",
+				"day": {"mintemp_c": 12.0, "maxtemp_c": 22.0, "daily_chance_of_rain": 10, "condition": {"text": "Sunny"}},
+				"astro": {"sunrise": "05:45 AM", "sunset": "08:50 PM", "moonrise": "11:00 PM"},
+				"hour": [
+					{"time": "2026-07-27 00:00", "temp_c": 13.0, "chance_of_rain": 5, "condition": {"text": "Clear"}, "wind_kph": 8.0}
+				]
+			}
+		]
+	}
+}`
+
+func withWeatherAPITestServer(t *testing.T, body string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	origCurrent, origForecast := weatherAPICurrentURL, weatherAPIForecastURL
+	weatherAPICurrentURL = server.URL
+	weatherAPIForecastURL = server.URL
+	t.Cleanup(func() {
+		weatherAPICurrentURL, weatherAPIForecastURL = origCurrent, origForecast
+	})
+
+	responseCache = NewResponseCache(defaultCacheTTL)
+}
+
+func TestWeatherAPIProviderCurrent(t *testing.T) {
+	withWeatherAPITestServer(t, currentFixture)
+
+	provider := &WeatherAPIProvider{apiKey: "test-key"}
+	observation, err := provider.Current(context.Background(), "London")
+	if err != nil {
+		t.Fatalf("Current: %v", err)
+	}
+
+	if observation.LocationName != "London" {
+		t.Errorf("LocationName = %q, want %q", observation.LocationName, "London")
+	}
+	if observation.TempC != 18.5 {
+		t.Errorf("TempC = %v, want %v", observation.TempC, 18.5)
+	}
+	if observation.TempF != 65.3 {
+		t.Errorf("TempF = %v, want %v", observation.TempF, 65.3)
+	}
+	if observation.Conditions != "Partly cloudy" {
+		t.Errorf("Conditions = %q, want %q", observation.Conditions, "Partly cloudy")
+	}
+}
+
+func TestWeatherAPIProviderForecast(t *testing.T) {
+	withWeatherAPITestServer(t, forecastFixture)
+
+	provider := &WeatherAPIProvider{apiKey: "test-key"}
+	days, err := provider.Forecast(context.Background(), "London", 1)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+
+	if len(days) != 1 {
+		t.Fatalf("got %d days, want 1", len(days))
+	}
+
+	day := days[0]
+	if day.Date != "2026-07-27" || day.MinC != 12.0 || day.MaxC != 22.0 || day.Conditions != "Sunny" {
+		t.Errorf("unexpected day: %+v", day)
+	}
+	if day.Sunrise != "05:45 AM" || day.Sunset != "08:50 PM" || day.Moonrise != "11:00 PM" {
+		t.Errorf("unexpected astro fields: %+v", day)
+	}
+	if len(day.Hours) != 1 || day.Hours[0].TempC != 13.0 || day.Hours[0].WindKph != 8.0 {
+		t.Errorf("unexpected hourly breakdown: %+v", day.Hours)
+	}
+}
+
+func TestWeatherAPIProviderForecastUpstreamError(t *testing.T) {
+	// weatherapi.com returns a well-formed JSON error body alongside a non-2xx status (e.g. a
+	// bad location or an exhausted quota), so this must fail on the status code rather than on
+	// a JSON-decode error.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 1006, "message": "No matching location found."}}`))
+	}))
+	defer server.Close()
+
+	origForecast := weatherAPIForecastURL
+	weatherAPIForecastURL = server.URL
+	defer func() { weatherAPIForecastURL = origForecast }()
+	responseCache = NewResponseCache(defaultCacheTTL)
+
+	provider := &WeatherAPIProvider{apiKey: "test-key"}
+	if _, err := provider.Forecast(context.Background(), "London", 1); err == nil {
+		t.Error("expected an error for a non-2xx upstream response, got nil")
+	}
+
+	if _, found := responseCache.Get(cacheKeyFor(providerWeatherAPI, "forecast:1", "London")); found {
+		t.Error("a non-2xx response must not be cached")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Observation represents a normalised current-conditions reading, independent of which
+// upstream weather provider produced it.
+type Observation struct {
+	LocationName string
+	TempC        float64
+	TempF        float64
+	Conditions   string
+}
+
+// DayForecast represents a normalised single day of a multi-day forecast, independent of
+// which upstream weather provider produced it. Hours holds the hourly breakdown used by
+// handleHourlyCommand: WeatherAPIProvider and OpenWeatherMapProvider fill it on every Forecast
+// call, while OpenMeteoProvider always leaves it empty.
+type DayForecast struct {
+	Date         string
+	MinC         float64
+	MaxC         float64
+	ChanceOfRain float64
+	Conditions   string
+	Sunrise      string
+	Sunset       string
+	Moonrise     string
+	Hours        []HourForecast
+}
+
+// HourForecast represents a single hour of the hourly breakdown within a DayForecast.
+type HourForecast struct {
+	Time         string
+	TempC        float64
+	ChanceOfRain float64
+	Conditions   string
+	WindKph      float64
+}
+
+// WeatherProvider is implemented by each backend we can fetch weather data from, so that
+// weatherHandler doesn't need to know whether it's talking to weatherapi.com, OpenWeatherMap
+// or Open-Meteo.
+type WeatherProvider interface {
+	Current(ctx context.Context, location string) (Observation, error)
+	Forecast(ctx context.Context, location string, days int) ([]DayForecast, error)
+}
+
+// providerName identifies one of the supported WeatherProvider implementations.
+type providerName string
+
+const (
+	providerWeatherAPI     providerName = "weatherapi"
+	providerOpenWeatherMap providerName = "openweathermap"
+	providerOpenMeteo      providerName = "openmeteo"
+)
+
+const defaultProvider providerName = providerWeatherAPI
+
+// newWeatherProvider constructs the WeatherProvider named by name, using the supplied
+// per-provider API keys where the provider requires one.
+func newWeatherProvider(name providerName, weatherAPIKey string, owmAPIKey string) (WeatherProvider, error) {
+	switch name {
+	case providerWeatherAPI, "":
+		if weatherAPIKey == "" {
+			return nil, fmt.Errorf("weatherapi provider selected but no API key was supplied")
+		}
+		return &WeatherAPIProvider{apiKey: weatherAPIKey}, nil
+	case providerOpenWeatherMap:
+		if owmAPIKey == "" {
+			return nil, fmt.Errorf("openweathermap provider selected but no API key was supplied")
+		}
+		return &OpenWeatherMapProvider{apiKey: owmAPIKey}, nil
+	case providerOpenMeteo:
+		return &OpenMeteoProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider: %s", name)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unitSystem identifies which measurement system a response should be rendered in.
+type unitSystem string
+
+const (
+	unitsMetric   unitSystem = "metric"
+	unitsImperial unitSystem = "imperial"
+)
+
+// defaultUnits is used when a request neither passes an explicit units flag nor has a saved
+// per-user preference.
+const defaultUnits unitSystem = unitsMetric
+
+// celsiusToFahrenheit converts a Celsius reading to Fahrenheit, the same formula used
+// elsewhere in this tool (see kelvinToFahrenheit in provider_openweathermap.go).
+func celsiusToFahrenheit(tempC float64) float64 {
+	return tempC*1.8 + 32
+}
+
+// kphToMph converts a km/h wind speed to mph.
+func kphToMph(windKph float64) float64 {
+	return windKph * 0.621371
+}
+
+// extractUnitsFlag pulls a trailing "--units metric|imperial" or shorthand "-f"/"-c" flag out
+// of a slash-command's argument fields, in the same spirit as the "--astro" flag handled by
+// parseForecastArgs/parseHourlyArgs. It returns the remaining fields, the requested unit system,
+// and whether a units flag was present at all (so callers can distinguish "asked for metric"
+// from "didn't ask").
+func extractUnitsFlag(fields []string) (remaining []string, units unitSystem, explicit bool) {
+	for i := 0; i < len(fields); i++ {
+		switch {
+		case fields[i] == "--units" && i+1 < len(fields):
+			switch strings.ToLower(fields[i+1]) {
+			case "imperial":
+				units, explicit = unitsImperial, true
+			case "metric":
+				units, explicit = unitsMetric, true
+			}
+			i++
+		case fields[i] == "-f":
+			units, explicit = unitsImperial, true
+		case fields[i] == "-c":
+			units, explicit = unitsMetric, true
+		default:
+			remaining = append(remaining, fields[i])
+		}
+	}
+	return remaining, units, explicit
+}
+
+// resolveUnits decides which unit system a response should use: an explicit flag always wins,
+// otherwise the user's saved preference (if any), otherwise defaultUnits.
+func resolveUnits(explicit bool, requested unitSystem, userID string) unitSystem {
+	if explicit {
+		return requested
+	}
+	if userID != "" {
+		if saved, ok := userPreferences.Get(userID); ok {
+			return saved
+		}
+	}
+	return defaultUnits
+}
+
+// formatTempC renders a Celsius reading under the given unit system, deriving Fahrenheit via
+// celsiusToFahrenheit. Use formatObservationTemp instead when a provider-native Fahrenheit
+// reading is available (e.g. Observation.TempF), so weatherapi.com's own temp_f isn't discarded.
+func formatTempC(tempC float64, units unitSystem) string {
+	if units == unitsImperial {
+		return fmt.Sprintf("%.1fÂ°F", celsiusToFahrenheit(tempC))
+	}
+	return fmt.Sprintf("%.1fÂ°C", tempC)
+}
+
+// formatObservationTemp renders a current-conditions reading under the given unit system,
+// using the provider's own TempF rather than re-deriving it from TempC.
+func formatObservationTemp(observation Observation, units unitSystem) string {
+	if units == unitsImperial {
+		return fmt.Sprintf("%.1fÂ°F", observation.TempF)
+	}
+	return fmt.Sprintf("%.1fÂ°C", observation.TempC)
+}
+
+// formatWindKph renders a km/h wind speed under the given unit system.
+func formatWindKph(windKph float64, units unitSystem) string {
+	if units == unitsImperial {
+		return fmt.Sprintf("%.1f mph", kphToMph(windKph))
+	}
+	return fmt.Sprintf("%.1f km/h", windKph)
+}
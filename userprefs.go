@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// defaultUserPrefsFile is where per-user unit preferences are persisted when no override is
+// configured.
+const defaultUserPrefsFile string = "user_prefs.json"
+
+// UserPreferenceStore persists each Mattermost user's preferred unit system to a small JSON
+// file on disk, keyed by their user_id, so they don't have to pass --units/-f/-c on every
+// command. It's intentionally simple (a mutex-guarded map rewritten to disk on every change)
+// rather than a database, matching the scale of the rest of this tool's config handling.
+type UserPreferenceStore struct {
+	mu    sync.RWMutex
+	path  string
+	units map[string]unitSystem
+}
+
+// NewUserPreferenceStore loads path into a new UserPreferenceStore. A missing file is treated
+// as an empty store rather than an error, since it simply means no preferences have been saved
+// yet.
+func NewUserPreferenceStore(path string) (*UserPreferenceStore, error) {
+	store := &UserPreferenceStore{path: path, units: make(map[string]unitSystem)}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &store.units); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Get returns the saved unit preference for userID, if any.
+func (s *UserPreferenceStore) Get(userID string) (unitSystem, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	units, ok := s.units[userID]
+	return units, ok
+}
+
+// Set saves units as userID's default and persists the store to disk.
+func (s *UserPreferenceStore) Set(userID string, units unitSystem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.units[userID] = units
+
+	raw, err := json.MarshalIndent(s.units, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, raw, 0o600)
+}
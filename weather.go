@@ -1,69 +1,53 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
-	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/buger/jsonparser"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 // Defaults & Type Definitions
 
-var debugMode bool = false
 var defaultPort string = "8080"
-var weatherAPIKey string
+var activeProvider WeatherProvider
+var responseCache *ResponseCache
+var userPreferences *UserPreferenceStore
 
 // MattermostResponse represents the key fields that we need to deliver in the
 // response to the Mattermost slash command.
 type MattermostResponse struct {
-	ResponseType string `json:"response_type"`
-	Text         string `json:"text"`
+	ResponseType string                 `json:"response_type"`
+	Text         string                 `json:"text"`
+	Attachments  []MattermostAttachment `json:"attachments,omitempty"`
 }
 
-// LogLevel is used to refer to the type of message that will be written using the logging code.
-type LogLevel string
+// MattermostAttachment represents a single Mattermost message attachment, used when we
+// want to render richer content (tables, colour-coded fields, etc.) than a bare text field allows.
+type MattermostAttachment struct {
+	Text string `json:"text,omitempty"`
+}
 
 const defaultConfigFile string = "config.json"
 
-const (
-	debugLevel   LogLevel = "DEBUG"
-	infoLevel    LogLevel = "INFO"
-	warningLevel LogLevel = "WARNING"
-	errorLevel   LogLevel = "ERROR"
-)
-
-const (
-	weatherAPIBase   string = "http://api.weatherapi.com/v1/current.json"
-	weatherAPISuffix string = "aqi=no"
-)
-
-// Logging functions
+const defaultForecastDays int = 3
+const maxForecastDays int = 10
 
-// LogMessage logs a formatted message to stdout or stderr
-func LogMessage(level LogLevel, message string) {
-	if level == errorLevel {
-		log.SetOutput(os.Stderr)
-	} else {
-		log.SetOutput(os.Stdout)
-	}
-	log.SetFlags(log.Ldate | log.Ltime)
-	log.Printf("[%s] %s\n", level, message)
-}
+// subcommandPattern pulls the subcommand keyword (forecast/hourly/metar) and the remainder of the
+// slash-command text apart, similar to how a bot might separate "metar KXXX" from other commands.
+var subcommandPattern = regexp.MustCompile(`^(forecast|hourly|metar)\s+(.*)$`)
 
-// DebugPrint allows us to add debug messages into our code, which are only printed if we're running in debug more.
-// Note that the command line parameter '-debug' can be used to enable this at runtime.
-func DebugPrint(message string) {
-	if debugMode {
-		LogMessage(debugLevel, message)
-	}
-}
+// setCommandPattern matches the "set units metric|imperial" management subcommand.
+var setCommandPattern = regexp.MustCompile(`^set\s+units\s+(\S+)$`)
 
 // Utility functions
 
@@ -77,120 +61,346 @@ func FileExists(filename string) (bool, error) {
 		return false, err
 	}
 	if info.IsDir() {
-		LogMessage(errorLevel, filename+" is a directory!")
+		logger.Error("path is a directory, not a file", zap.String("path", filename))
 		return false, nil
 	}
 	return true, nil
 }
 
+// buildMattermostResponse assembles the JSON payload we send back to Mattermost. responseType
+// should be "in_channel" or "ephemeral" per the slash-command contract.
+func buildMattermostResponse(responseType string, text string) MattermostResponse {
+	return MattermostResponse{
+		ResponseType: responseType,
+		Text:         text,
+	}
+}
+
+// writeMattermostResponse marshals payload and writes it to responseWriter with a 200 status,
+// logging any failure.
+func writeMattermostResponse(responseWriter http.ResponseWriter, payload MattermostResponse) {
+	writeMattermostResponseWithStatus(responseWriter, http.StatusOK, payload)
+}
+
+// writeMattermostResponseWithStatus marshals payload and writes it to responseWriter under the
+// given HTTP status code, logging any failure.
+func writeMattermostResponseWithStatus(responseWriter http.ResponseWriter, statusCode int, payload MattermostResponse) {
+	jsonResponse, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(statusCode)
+	_, writeErr := responseWriter.Write(jsonResponse)
+	if writeErr != nil {
+		logger.Error("error posting response to Mattermost", zap.Error(writeErr))
+	}
+}
+
 // Integration functions
 
-// weatherHandler is the primary function for processing the incoming slash command
+// weatherHandler is the primary function for processing the incoming slash command. The
+// command's "text" field (from the POST body, or the query string in insecure GET mode) carries
+// both the default "<location>" form and, optionally, a subcommand such as
+// "forecast <location> [days] [--astro]", "hourly <location> [--astro]", "metar <ICAO>" or
+// "set units metric|imperial". The default, forecast and hourly forms also accept a trailing
+// "--units metric|imperial" (or shorthand "-f"/"-c") flag; metar does not.
 func weatherHandler(responseWriter http.ResponseWriter, inboundRequest *http.Request) {
-	LogMessage(infoLevel, "Received inbound request")
+	ctx := withCorrelationID(inboundRequest.Context(), newCorrelationID())
+	log := requestLogger(ctx)
+	log.Info("received inbound request")
+
+	text, userID, ok := authenticateSlashCommand(ctx, responseWriter, inboundRequest)
+	if !ok {
+		return
+	}
+	log.Debug("parsed slash command", zap.String("text", text))
+
+	trimmedText := strings.TrimSpace(text)
 
-	// Retrieve the location from the GET request
-	location := inboundRequest.URL.Query().Get("text")
-	DebugPrint("Text: " + location)
+	if matches := setCommandPattern.FindStringSubmatch(trimmedText); matches != nil {
+		handleSetCommand(ctx, responseWriter, userID, matches[1])
+		return
+	}
+
+	if matches := subcommandPattern.FindStringSubmatch(trimmedText); matches != nil {
+		switch matches[1] {
+		case "forecast":
+			handleForecastCommand(ctx, responseWriter, userID, matches[2])
+			return
+		case "hourly":
+			handleHourlyCommand(ctx, responseWriter, userID, matches[2])
+			return
+		case "metar":
+			handleMetarCommand(ctx, responseWriter, matches[2])
+			return
+		}
+	}
+
+	fields, requestedUnits, explicit := extractUnitsFlag(strings.Fields(text))
+	units := resolveUnits(explicit, requestedUnits, userID)
+
+	location := strings.Join(fields, " ")
 	if location == "" {
 		location = "auto:ip"
 	}
 
-	// Call the backend API
-	apiResponse, err := callWeatherAPI(location)
+	observation, err := activeProvider.Current(ctx, location)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentLocation, _ := jsonparser.GetString([]byte(apiResponse), "location", "name")
-	currentTemp, _ := jsonparser.GetFloat([]byte(apiResponse), "current", "temp_c")
-	currentConditions, _ := jsonparser.GetString([]byte(apiResponse), "current", "condition", "text")
-
 	// responseMessage contains a Markdown message that gets posted to the channel
-	responseMessage := fmt.Sprintf("Current weather in %s: %vÂ°C - %s", currentLocation, currentTemp, currentConditions)
+	responseMessage := fmt.Sprintf("Current weather in %s: %s - %s", observation.LocationName, formatObservationTemp(observation, units), observation.Conditions)
 
 	// responsePayload.ResponseType can be "in_channel" to be posted to the whole channel, or "ephemeral"
 	// to be only visible to the person running the slash command.
-	responsePayload := MattermostResponse{
-		ResponseType: "in_channel",
-		Text:         responseMessage,
+	writeMattermostResponse(responseWriter, buildMattermostResponse("in_channel", responseMessage))
+}
+
+// handleForecastCommand parses the remainder of a "forecast" subcommand (location, optional day
+// count, "--astro" and units flag), calls the forecast API and writes a Markdown table response.
+func handleForecastCommand(ctx context.Context, responseWriter http.ResponseWriter, userID string, args string) {
+	location, days, astro, units := parseForecastArgs(args, userID)
+
+	forecastDays, err := activeProvider.Forecast(ctx, location, days)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	// Marshal the response payload to JSON
-	jsonResponse, err := json.Marshal(responsePayload)
+	message := formatForecastResponse(location, forecastDays, astro, units)
+	writeMattermostResponse(responseWriter, buildMattermostResponse("in_channel", message))
+}
+
+// handleHourlyCommand parses the remainder of an "hourly" subcommand (location, optional
+// "--astro" and units flag), calls the forecast API for a single day and writes a Markdown
+// table response.
+func handleHourlyCommand(ctx context.Context, responseWriter http.ResponseWriter, userID string, args string) {
+	location, astro, units := parseHourlyArgs(args, userID)
+
+	forecastDays, err := activeProvider.Forecast(ctx, location, 1)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Write the JSON response back to Mattermost
-	responseWriter.Header().Set("Content-Type", "application/json")
-	responseWriter.WriteHeader(http.StatusOK)
-	_, writeErr := responseWriter.Write(jsonResponse)
-	if writeErr != nil {
-		LogMessage(errorLevel, "Error posting response to Mattermost: "+string(writeErr.Error()))
+	message := formatHourlyResponse(location, forecastDays, astro, units)
+	writeMattermostResponse(responseWriter, buildMattermostResponse("in_channel", message))
+}
+
+// handleSetCommand implements "set units metric|imperial", persisting the caller's default unit
+// system so they don't have to pass --units/-f/-c on every command.
+func handleSetCommand(ctx context.Context, responseWriter http.ResponseWriter, userID string, requested string) {
+	log := requestLogger(ctx)
+
+	var units unitSystem
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "metric":
+		units = unitsMetric
+	case "imperial":
+		units = unitsImperial
+	default:
+		writeMattermostResponse(responseWriter, buildMattermostResponse("ephemeral", "Usage: `/weather set units metric|imperial`"))
+		return
+	}
+
+	if userID == "" {
+		writeMattermostResponse(responseWriter, buildMattermostResponse("ephemeral", "Unable to determine your Mattermost user ID; your preference was not saved."))
+		return
 	}
+
+	if err := userPreferences.Set(userID, units); err != nil {
+		log.Error("failed to persist user unit preference", zap.String("user_id", userID), zap.Error(err))
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeMattermostResponse(responseWriter, buildMattermostResponse("ephemeral", fmt.Sprintf("Your default units are now set to %s.", units)))
 }
 
-// callWeatherAPI is the code that, as the name suggests, actually calls out to the weather API
-func callWeatherAPI(location string) (string, error) {
-	DebugPrint("Calling weather API for location: " + location)
+// parseForecastArgs splits "<location> [days] [--astro] [--units metric|imperial]" into its
+// component parts, defaulting days to defaultForecastDays (capped at maxForecastDays) and units
+// to userID's saved preference (or defaultUnits) when no units flag is present. A trailing bare
+// integer is only treated as the day count when at least one other location token precedes it
+// (numeric or not, so "10001 5" is ZIP 10001 for 5 days); a lone integer is left as the
+// location, so numeric locations weatherapi.com supports (e.g. a US ZIP, "90210") aren't
+// swallowed as a day count.
+func parseForecastArgs(args string, userID string) (location string, days int, astro bool, units unitSystem) {
+	fields, requestedUnits, explicit := extractUnitsFlag(strings.Fields(args))
+	units = resolveUnits(explicit, requestedUnits, userID)
+	days = defaultForecastDays
+
+	var locationParts []string
+	for _, field := range fields {
+		if field == "--astro" {
+			astro = true
+			continue
+		}
+		locationParts = append(locationParts, field)
+	}
 
-	safeLocation := url.QueryEscape(location)
+	if len(locationParts) > 1 {
+		lastField := locationParts[len(locationParts)-1]
+		if parsedDays, err := strconv.Atoi(lastField); err == nil {
+			days = parsedDays
+			locationParts = locationParts[:len(locationParts)-1]
+		}
+	}
 
-	fullURL := fmt.Sprintf("%s?key=%s&q=%s&%s", weatherAPIBase, weatherAPIKey, safeLocation, weatherAPISuffix)
+	if days > maxForecastDays {
+		days = maxForecastDays
+	}
+	if days < 1 {
+		days = 1
+	}
 
-	// Make the GET call to retrieve the data
-	resp, err := http.Get(fullURL)
-	if err != nil {
-		LogMessage(errorLevel, "Call to Weather API failed")
-		return "", err
+	location = strings.Join(locationParts, " ")
+	if location == "" {
+		location = "auto:ip"
 	}
-	defer resp.Body.Close()
+	return location, days, astro, units
+}
 
-	// Extract the body of the message
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		LogMessage(errorLevel, "Unable to extract body data from Weather API response")
-		return "", err
+// parseHourlyArgs splits "<location> [--astro] [--units metric|imperial]" into its component
+// parts, resolving units the same way parseForecastArgs does.
+func parseHourlyArgs(args string, userID string) (location string, astro bool, units unitSystem) {
+	fields, requestedUnits, explicit := extractUnitsFlag(strings.Fields(args))
+	units = resolveUnits(explicit, requestedUnits, userID)
+
+	var locationParts []string
+	for _, field := range fields {
+		if field == "--astro" {
+			astro = true
+			continue
+		}
+		locationParts = append(locationParts, field)
 	}
 
-	// Parse the response
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		LogMessage(errorLevel, "Failed to convert body data")
-		return "", err
+	location = strings.Join(locationParts, " ")
+	if location == "" {
+		location = "auto:ip"
 	}
+	return location, astro, units
+}
 
-	// Convert the data to a string to return to the calling function
-	weatherData, err := json.Marshal(result)
-	if err != nil {
-		LogMessage(errorLevel, "Unable to convert weather data to string")
-		return "", err
+// formatForecastResponse renders up to maxForecastDays of daily min/max temps, chance of rain and
+// conditions as a Markdown table, optionally adding an Astronomy column with each day's
+// sunrise/sunset/moonrise.
+func formatForecastResponse(location string, forecastDays []DayForecast, astro bool, units unitSystem) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "#### Forecast for %s\n\n", location)
+
+	if astro {
+		builder.WriteString("| Date | Min | Max | Chance of Rain | Conditions | Astronomy |\n")
+		builder.WriteString("|---|---|---|---|---|---|\n")
+	} else {
+		builder.WriteString("| Date | Min | Max | Chance of Rain | Conditions |\n")
+		builder.WriteString("|---|---|---|---|---|\n")
+	}
+
+	for _, day := range forecastDays {
+		if astro {
+			fmt.Fprintf(&builder, "| %s | %s | %s | %v%% | %s | Sunrise: %s, Sunset: %s, Moonrise: %s |\n",
+				day.Date, formatTempC(day.MinC, units), formatTempC(day.MaxC, units), day.ChanceOfRain, day.Conditions,
+				day.Sunrise, day.Sunset, day.Moonrise)
+			continue
+		}
+		fmt.Fprintf(&builder, "| %s | %s | %s | %v%% | %s |\n", day.Date, formatTempC(day.MinC, units), formatTempC(day.MaxC, units), day.ChanceOfRain, day.Conditions)
+	}
+
+	return builder.String()
+}
+
+// formatHourlyResponse renders the hourly breakdown (time, temp, chance of rain, conditions,
+// wind) for the first forecast day as a Markdown table, optionally appending astronomy data.
+func formatHourlyResponse(location string, forecastDays []DayForecast, astro bool, units unitSystem) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "#### Hourly forecast for %s\n\n", location)
+	builder.WriteString("| Time | Temp | Chance of Rain | Conditions | Wind |\n")
+	builder.WriteString("|---|---|---|---|---|\n")
+
+	if len(forecastDays) == 0 {
+		return builder.String()
+	}
+	firstDay := forecastDays[0]
+
+	for _, hour := range firstDay.Hours {
+		fmt.Fprintf(&builder, "| %s | %s | %v%% | %s | %s |\n", hour.Time, formatTempC(hour.TempC, units), hour.ChanceOfRain, hour.Conditions, formatWindKph(hour.WindKph, units))
 	}
 
-	DebugPrint("Weather data: " + string(weatherData))
+	if astro {
+		fmt.Fprintf(&builder, "\nSunrise: %s | Sunset: %s | Moonrise: %s\n", firstDay.Sunrise, firstDay.Sunset, firstDay.Moonrise)
+	}
 
-	return string(weatherData), nil
+	return builder.String()
 }
 
 func main() {
-	var debugFlag bool
+	var logLevelFlag string
+	var logFormatFlag string
 	var configFile string
 	var apiToken string
 	var listenPort string
-
-	flag.BoolVar(&debugFlag, "debug", false, "Enable debug mode")
+	var providerFlag string
+	var metarToken string
+	var cacheTTLFlag string
+	var insecureGetFlag bool
+	var userPrefsFile string
+
+	flag.StringVar(&logLevelFlag, "log-level", "", "Override the log level supplied in the config file (debug, info, warn, error)")
+	flag.StringVar(&logFormatFlag, "log-format", "", "Override the log format supplied in the config file (console, json)")
 	flag.StringVar(&configFile, "config", "config.json", "Override default config file (config.json)")
 	flag.StringVar(&apiToken, "token", "", "Override the API token supplied in the config file")
 	flag.StringVar(&listenPort, "port", "", "Override the port that this utility should listen on")
+	flag.StringVar(&providerFlag, "provider", "", "Override the weather provider supplied in the config file (weatherapi, openweathermap, openmeteo)")
+	flag.StringVar(&metarToken, "metar-token", "", "Override the METAR (avwx.rest) API token supplied in the config file")
+	flag.StringVar(&cacheTTLFlag, "cache-ttl", "", "Override the response cache TTL supplied in the config file, e.g. \"10m\"")
+	flag.BoolVar(&insecureGetFlag, "insecure-get", false, "Allow legacy unauthenticated GET requests to /weather (migration only)")
+	flag.StringVar(&userPrefsFile, "user-prefs-file", "", "Override the per-user unit preferences file supplied in the config file")
 
 	flag.Parse()
 
-	debugMode = debugFlag
 	var exists bool
 
+	// We always need the config file loaded (even if every value ends up overridden by flag/env)
+	// so that provider-specific keys are available below.
+	viper.SetConfigFile(configFile)
+	configErr := viper.ReadInConfig()
+
+	// Resolve the log level/format, following the same flag > env > config precedence as
+	// everything else, then build the package logger before anything else runs so that even
+	// early startup messages go through it.
+	if logLevelFlag == "" {
+		logLevelFlag, exists = os.LookupEnv("LOG_LEVEL")
+		if !exists {
+			logLevelFlag = viper.GetString("logLevel")
+		}
+	}
+	if logLevelFlag == "" {
+		logLevelFlag = "info"
+	}
+
+	if logFormatFlag == "" {
+		logFormatFlag, exists = os.LookupEnv("LOG_FORMAT")
+		if !exists {
+			logFormatFlag = viper.GetString("logFormat")
+		}
+	}
+	if logFormatFlag == "" {
+		logFormatFlag = "json"
+	}
+
+	builtLogger, err := newLogger(logLevelFlag, logFormatFlag)
+	if err != nil {
+		panic(fmt.Errorf("invalid log level %q: %w", logLevelFlag, err))
+	}
+	logger = builtLogger
+	defer logger.Sync()
+
 	// If the API token is not passed on the command line, we should check whether it exists as an
 	// environment variable before reading the value from the config file.
 	if apiToken == "" {
@@ -198,27 +408,18 @@ func main() {
 		apiToken, exists = os.LookupEnv("WEATHER_API_TOKEN")
 		if !exists {
 			// Still no API token - let's check the config file
-			viper.SetConfigFile(configFile)
-			err := viper.ReadInConfig()
-			if err != nil {
-				panic(fmt.Errorf("fatal error processing config file: %w", err))
+			if configErr != nil {
+				panic(fmt.Errorf("fatal error processing config file: %w", configErr))
 			}
 			apiToken = viper.GetString("apiKey")
-			DebugPrint("Obtained API key from config file")
+			logger.Debug("obtained API key from config file")
 		} else {
-			DebugPrint("Obtained API key from environment")
+			logger.Debug("obtained API key from environment")
 		}
 	} else {
-		DebugPrint("Obtained API key from command line")
+		logger.Debug("obtained API key from command line")
 	}
 
-	if apiToken == "" {
-		LogMessage(errorLevel, "Failed to locate API key!")
-		os.Exit(2)
-	}
-
-	weatherAPIKey = apiToken
-
 	// In the same way that we validated the API key, we need a valid port parameter, except in this case
 	// we have a programmatic default
 	if listenPort == "" {
@@ -229,22 +430,130 @@ func main() {
 			listenPort = viper.GetString("listenPort")
 			if listenPort == "" {
 				listenPort = defaultPort
-				DebugPrint("Using default listen port: " + listenPort)
+				logger.Debug("using default listen port", zap.String("port", listenPort))
 			} else {
-				DebugPrint("Obtained listen port '" + listenPort + "' from config file")
+				logger.Debug("obtained listen port from config file", zap.String("port", listenPort))
 			}
 		} else {
-			DebugPrint("Obtained listen port '" + listenPort + "' from environment")
+			logger.Debug("obtained listen port from environment", zap.String("port", listenPort))
 		}
 	} else {
-		DebugPrint("Obtained listen port '" + listenPort + "' from command line")
+		logger.Debug("obtained listen port from command line", zap.String("port", listenPort))
+	}
+
+	// Resolve which WeatherProvider backend to use, following the same flag > env > config
+	// precedence as everything else, then construct it with whichever API key(s) it needs.
+	selectedProvider := providerName(providerFlag)
+	if selectedProvider == "" {
+		if envProvider, set := os.LookupEnv("WEATHER_PROVIDER"); set {
+			selectedProvider = providerName(envProvider)
+			logger.Debug("obtained weather provider from environment", zap.String("provider", envProvider))
+		} else if configProvider := viper.GetString("provider"); configProvider != "" {
+			selectedProvider = providerName(configProvider)
+			logger.Debug("obtained weather provider from config file", zap.String("provider", configProvider))
+		} else {
+			selectedProvider = defaultProvider
+			logger.Debug("using default weather provider", zap.String("provider", string(defaultProvider)))
+		}
+	} else {
+		logger.Debug("obtained weather provider from command line", zap.String("provider", providerFlag))
+	}
+
+	owmAPIKey := viper.GetString("openWeatherMapApiKey")
+
+	// The METAR subcommand is optional, so an absent token just means that subcommand will
+	// fail at request time rather than preventing the service from starting.
+	if metarToken == "" {
+		metarToken, exists = os.LookupEnv("METAR_API_TOKEN")
+		if !exists {
+			metarToken = viper.GetString("metarApiKey")
+			logger.Debug("obtained METAR API key from config file")
+		} else {
+			logger.Debug("obtained METAR API key from environment")
+		}
+	} else {
+		logger.Debug("obtained METAR API key from command line")
+	}
+	metarAPIKey = metarToken
+
+	provider, err := newWeatherProvider(selectedProvider, apiToken, owmAPIKey)
+	if err != nil {
+		logger.Error("failed to construct weather provider", zap.Error(err))
+		os.Exit(2)
+	}
+	activeProvider = provider
+
+	// Resolve the response cache TTL, following the same flag > env > config precedence as
+	// everything else.
+	if cacheTTLFlag == "" {
+		cacheTTLFlag, exists = os.LookupEnv("WEATHER_CACHE_TTL")
+		if !exists {
+			cacheTTLFlag = viper.GetString("cacheTTL")
+			logger.Debug("obtained cache TTL from config file", zap.String("cacheTTL", cacheTTLFlag))
+		} else {
+			logger.Debug("obtained cache TTL from environment", zap.String("cacheTTL", cacheTTLFlag))
+		}
+	} else {
+		logger.Debug("obtained cache TTL from command line", zap.String("cacheTTL", cacheTTLFlag))
+	}
+
+	cacheTTL := defaultCacheTTL
+	if cacheTTLFlag != "" {
+		parsedTTL, parseErr := time.ParseDuration(cacheTTLFlag)
+		if parseErr != nil {
+			logger.Warn("invalid cache TTL, falling back to default", zap.String("cacheTTL", cacheTTLFlag), zap.Error(parseErr))
+		} else {
+			cacheTTL = parsedTTL
+		}
+	}
+
+	responseCache = NewResponseCache(cacheTTL)
+	responseCache.StartJanitor(janitorInterval)
+
+	// Resolve the per-user preferences file, following the same flag > env > config precedence
+	// as everything else.
+	if userPrefsFile == "" {
+		userPrefsFile, exists = os.LookupEnv("WEATHER_USER_PREFS_FILE")
+		if !exists {
+			userPrefsFile = viper.GetString("userPrefsFile")
+		}
+	}
+	if userPrefsFile == "" {
+		userPrefsFile = defaultUserPrefsFile
+	}
+
+	prefsStore, err := NewUserPreferenceStore(userPrefsFile)
+	if err != nil {
+		logger.Error("failed to load user preferences file", zap.String("path", userPrefsFile), zap.Error(err))
+		os.Exit(2)
+	}
+	userPreferences = prefsStore
+
+	insecureGetMode = insecureGetFlag
+
+	if tokensEnv, tokensSet := os.LookupEnv("MM_SLASH_TOKENS"); tokensSet {
+		for _, token := range strings.Split(tokensEnv, ",") {
+			if trimmed := strings.TrimSpace(token); trimmed != "" {
+				mattermostTokens = append(mattermostTokens, trimmed)
+			}
+		}
+		logger.Debug("obtained Mattermost slash-command tokens from environment")
+	} else {
+		mattermostTokens = viper.GetStringSlice("mattermostTokens")
+		logger.Debug("obtained Mattermost slash-command tokens from config file")
+	}
+
+	if len(mattermostTokens) == 0 && !insecureGetMode {
+		logger.Error("no Mattermost slash-command tokens configured; refusing to start (pass -insecure-get for migration)")
+		os.Exit(2)
 	}
 
 	// Setup the inbound request handler
-	LogMessage(infoLevel, "Starting server on port "+listenPort)
+	logger.Info("starting server", zap.String("port", listenPort))
 	listenPortString := fmt.Sprintf(":%s", listenPort)
-	DebugPrint("Listen port string: " + listenPortString)
+	logger.Debug("listen port string", zap.String("listenPortString", listenPortString))
 	http.HandleFunc("/weather", weatherHandler)
+	http.HandleFunc("/metrics", metricsHandler)
 	if err := http.ListenAndServe(listenPortString, nil); err != nil {
 		panic(err)
 	}
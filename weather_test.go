@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseForecastArgs(t *testing.T) {
+	userPreferences = &UserPreferenceStore{units: make(map[string]unitSystem)}
+
+	tests := []struct {
+		name      string
+		args      string
+		wantLoc   string
+		wantDays  int
+		wantAstro bool
+		wantUnits unitSystem
+	}{
+		{"defaults", "London", "London", defaultForecastDays, false, defaultUnits},
+		{"explicit days", "Paris 5", "Paris", 5, false, defaultUnits},
+		{"astro flag", "Tokyo --astro", "Tokyo", defaultForecastDays, true, defaultUnits},
+		{"units flag", "Berlin --units imperial", "Berlin", defaultForecastDays, false, unitsImperial},
+		{"days capped", "Rome 99", "Rome", maxForecastDays, false, defaultUnits},
+		{"days floored", "Rome 0", "Rome", 1, false, defaultUnits},
+		{"multi word location", "New York 4 --astro", "New York", 4, true, defaultUnits},
+		{"bare numeric location is a zip, not a day count", "90210", "90210", defaultForecastDays, false, defaultUnits},
+		{"zip with an explicit day count", "10001 5", "10001", 5, false, defaultUnits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, days, astro, units := parseForecastArgs(tt.args, "")
+			if location != tt.wantLoc {
+				t.Errorf("location = %q, want %q", location, tt.wantLoc)
+			}
+			if days != tt.wantDays {
+				t.Errorf("days = %d, want %d", days, tt.wantDays)
+			}
+			if astro != tt.wantAstro {
+				t.Errorf("astro = %v, want %v", astro, tt.wantAstro)
+			}
+			if units != tt.wantUnits {
+				t.Errorf("units = %v, want %v", units, tt.wantUnits)
+			}
+		})
+	}
+}
+
+func TestParseForecastArgsUsesSavedPreference(t *testing.T) {
+	userPreferences = &UserPreferenceStore{path: t.TempDir() + "/user_prefs.json", units: make(map[string]unitSystem)}
+	if err := userPreferences.Set("user-1", unitsImperial); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	_, _, _, units := parseForecastArgs("London", "user-1")
+	if units != unitsImperial {
+		t.Errorf("units = %v, want %v", units, unitsImperial)
+	}
+}
+
+func TestParseHourlyArgs(t *testing.T) {
+	userPreferences = &UserPreferenceStore{units: make(map[string]unitSystem)}
+
+	location, astro, units := parseHourlyArgs("San Francisco --astro --units imperial", "")
+	if location != "San Francisco" {
+		t.Errorf("location = %q, want %q", location, "San Francisco")
+	}
+	if !astro {
+		t.Error("astro = false, want true")
+	}
+	if units != unitsImperial {
+		t.Errorf("units = %v, want %v", units, unitsImperial)
+	}
+}
+
+func TestFormatForecastResponse(t *testing.T) {
+	days := []DayForecast{
+		{Date: "2026-07-27", MinC: 10, MaxC: 20, ChanceOfRain: 30, Conditions: "Sunny"},
+	}
+
+	message := formatForecastResponse("London", days, false, unitsMetric)
+	if !strings.Contains(message, "| Date | Min | Max | Chance of Rain | Conditions |") {
+		t.Errorf("expected a 5-column header, got: %s", message)
+	}
+	if strings.Contains(message, "Astronomy") {
+		t.Errorf("did not expect an Astronomy column when astro is false, got: %s", message)
+	}
+}
+
+func TestFormatForecastResponseWithAstro(t *testing.T) {
+	days := []DayForecast{
+		{Date: "2026-07-27", MinC: 10, MaxC: 20, ChanceOfRain: 30, Conditions: "Sunny", Sunrise: "06:00", Sunset: "20:00", Moonrise: "22:00"},
+	}
+
+	message := formatForecastResponse("London", days, true, unitsMetric)
+	for _, want := range []string{"Astronomy", "Sunrise: 06:00", "Sunset: 20:00", "Moonrise: 22:00"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, message)
+		}
+	}
+
+	// The astro row must stay a well-formed table row (one line, six cells), not a filler row
+	// spliced in between daily rows.
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	lastLine := lines[len(lines)-1]
+	if strings.Count(lastLine, "|") != 7 {
+		t.Errorf("expected a single 6-column row, got: %q", lastLine)
+	}
+}